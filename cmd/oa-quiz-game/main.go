@@ -0,0 +1,109 @@
+// Command oa-quiz-game is a terminal arithmetic quiz game. It runs
+// interactively by default, as a scripted headless benchmark with
+// --headless, or as a networked versus match via the "host"/"join"
+// subcommands.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/skizpow7/oa-quiz-game/game"
+	"github.com/skizpow7/oa-quiz-game/theme"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "host":
+			runHost(os.Args[2:])
+			return
+		case "join":
+			runJoin(os.Args[2:])
+			return
+		}
+	}
+
+	headless := flag.Bool("headless", false, "run a scripted, non-interactive quiz and print a JSON transcript")
+	difficulty := flag.String("difficulty", "5th Grade", "difficulty name, as shown in the interactive menu")
+	duration := flag.Int("duration", 60, "quiz duration in seconds (headless mode)")
+	seed := flag.Int64("seed", 1, "RNG seed for reproducible question generation (headless mode)")
+	answers := flag.String("answers", "", "path to a file of answers to replay (headless mode)")
+	themeName := flag.String("theme", "default", "color theme: default, solarized, high-contrast, colorblind-deuteranopia, or a custom name with --theme-file")
+	themeFile := flag.String("theme-file", "", "path to a custom TOML theme, used when --theme doesn't name a built-in")
+	flag.Parse()
+
+	if *headless {
+		if *answers == "" {
+			fmt.Println("error: --headless requires --answers=<path>")
+			os.Exit(1)
+		}
+		cfg := game.Config{
+			Difficulty:  *difficulty,
+			Duration:    *duration,
+			Seed:        *seed,
+			AnswersPath: *answers,
+		}
+		if err := game.RunHeadless(cfg); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	th, err := theme.Load(*themeName, *themeFile)
+	if err != nil {
+		fmt.Println("error loading theme:", err)
+		os.Exit(1)
+	}
+
+	if err := game.RunInteractive(th); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+}
+
+func runHost(args []string) {
+	fs := flag.NewFlagSet("host", flag.ExitOnError)
+	port := fs.Int("port", 7777, "TCP port to listen on")
+	name := fs.String("name", "Host", "your display name")
+	themeName := fs.String("theme", "default", "color theme: default, solarized, high-contrast, colorblind-deuteranopia, or a custom name with --theme-file")
+	themeFile := fs.String("theme-file", "", "path to a custom TOML theme, used when --theme doesn't name a built-in")
+	fs.Parse(args)
+
+	th, err := theme.Load(*themeName, *themeFile)
+	if err != nil {
+		fmt.Println("error loading theme:", err)
+		os.Exit(1)
+	}
+
+	if err := game.RunHost(*port, *name, th); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+}
+
+func runJoin(args []string) {
+	fs := flag.NewFlagSet("join", flag.ExitOnError)
+	name := fs.String("name", "Player", "your display name")
+	themeName := fs.String("theme", "default", "color theme: default, solarized, high-contrast, colorblind-deuteranopia, or a custom name with --theme-file")
+	themeFile := fs.String("theme-file", "", "path to a custom TOML theme, used when --theme doesn't name a built-in")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Println("usage: oa-quiz-game join <host:port> --name <name>")
+		os.Exit(1)
+	}
+
+	th, err := theme.Load(*themeName, *themeFile)
+	if err != nil {
+		fmt.Println("error loading theme:", err)
+		os.Exit(1)
+	}
+
+	if err := game.RunJoin(fs.Arg(0), *name, th); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skizpow7/oa-quiz-game/questions"
+)
+
+// Config configures a headless run.
+type Config struct {
+	Difficulty  string
+	Duration    int // seconds
+	Seed        int64
+	AnswersPath string
+}
+
+// Transcript is the JSON document RunHeadless writes to stdout,
+// compatible with the interactive mode's AnswerRecord shape.
+type Transcript struct {
+	Difficulty string         `json:"difficulty"`
+	Seed       int64          `json:"seed"`
+	Answers    []AnswerRecord `json:"answers"`
+	Correct    int            `json:"correct"`
+	Total      int            `json:"total"`
+}
+
+// RunHeadless drives the same question-generation path as the
+// interactive TUI, using a seeded RNG for reproducibility, and
+// replays answers from AnswersPath instead of reading a keyboard.
+// Each line in that file is "<answer>" or "<answer> <duration_ms>".
+// It writes the resulting Transcript as JSON to stdout.
+func RunHeadless(cfg Config) error {
+	if dir, err := packsDir(); err == nil {
+		_ = questions.LoadAndRegisterPacks(dir) // missing/invalid packs just mean none are available
+	}
+
+	if _, ok := questions.Get(cfg.Difficulty); !ok {
+		return fmt.Errorf("game: unknown difficulty %q", cfg.Difficulty)
+	}
+
+	data, err := os.ReadFile(cfg.AnswersPath)
+	if err != nil {
+		return fmt.Errorf("game: reading answers file: %w", err)
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	used := make(map[string]struct{})
+	limit := time.Duration(cfg.Duration) * time.Second
+
+	transcript := Transcript{Difficulty: cfg.Difficulty, Seed: cfg.Seed}
+
+	var elapsed time.Duration
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if elapsed >= limit {
+			break
+		}
+
+		duration := time.Second
+		if len(fields) > 1 {
+			if ms, err := strconv.Atoi(fields[1]); err == nil {
+				duration = time.Duration(ms) * time.Millisecond
+			}
+		}
+
+		q := questions.Generate(cfg.Difficulty, rng, used)
+		userAns, _ := strconv.Atoi(fields[0])
+		correct := userAns == q.Answer
+
+		transcript.Answers = append(transcript.Answers, AnswerRecord{
+			Question:  q,
+			Correct:   correct,
+			Duration:  duration,
+			UserInput: fields[0],
+		})
+		transcript.Total++
+		if correct {
+			transcript.Correct++
+		}
+		elapsed += duration
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(transcript)
+}
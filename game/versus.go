@@ -0,0 +1,559 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	protocol "github.com/skizpow7/oa-quiz-game/net"
+	"github.com/skizpow7/oa-quiz-game/questions"
+	"github.com/skizpow7/oa-quiz-game/theme"
+)
+
+// reconnectGrace is how long a versus match waits for a dropped
+// connection to come back before ending the match outright.
+const reconnectGrace = 5 * time.Second
+
+// tieBreakerDuration is how long a tie-breaker round runs.
+const tieBreakerDuration = 15
+
+// versusSession holds the networking state for a head-to-head match.
+// It is nil on a model playing solo.
+//
+// conn/raw/oppName are written both by the main bubbletea Update loop
+// (at session setup) and by the background accept/redial goroutines
+// started on a disconnect, so all access to them goes through the
+// locked accessors below rather than bare field reads/writes.
+type versusSession struct {
+	mu       sync.Mutex
+	conn     *protocol.Conn
+	raw      net.Conn
+	oppName  string
+	listener net.Listener // set on the host only
+	addr     string       // set on the client only, used to redial on reconnect
+
+	isHost   bool
+	selfName string
+
+	selfScore int
+	oppScore  int
+	oppRemain int
+
+	disconnected      bool
+	reconnectDeadline time.Time
+	tieBreaker        bool
+
+	events chan versusEventMsg
+}
+
+// setConn atomically updates the live connection after a (re)connect.
+func (v *versusSession) setConn(raw net.Conn, conn *protocol.Conn) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.raw = raw
+	v.conn = conn
+}
+
+// getConn returns the current connection, safe to call while a
+// background reconnect goroutine may be replacing it.
+func (v *versusSession) getConn() *protocol.Conn {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.conn
+}
+
+// setOppName records the opponent's name once their Join arrives.
+func (v *versusSession) setOppName(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.oppName = name
+}
+
+// getOppName returns the opponent's name, or "" before they've joined.
+func (v *versusSession) getOppName() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.oppName
+}
+
+// versusEventMsg is what the background read/accept goroutines feed
+// back into bubbletea's Update loop.
+type versusEventMsg struct {
+	kind protocol.MessageType // "" for connect/disconnect plumbing below
+	data []byte
+	err  error
+}
+
+const (
+	versusKindConnected    protocol.MessageType = "__connected"
+	versusKindDisconnected protocol.MessageType = "__disconnected"
+)
+
+// waitVersusEvent returns a tea.Cmd that blocks for the next event on
+// the session's channel, re-arming itself is the caller's job (each
+// received event triggers another waitVersusEvent in Update).
+func waitVersusEvent(v *versusSession) tea.Cmd {
+	return func() tea.Msg {
+		return <-v.events
+	}
+}
+
+// versusReadLoop continuously reads protocol messages off conn and
+// forwards them as events, until the connection errors out.
+func versusReadLoop(v *versusSession, conn *protocol.Conn) {
+	for {
+		kind, data, err := conn.Receive()
+		if err != nil {
+			v.events <- versusEventMsg{kind: versusKindDisconnected, err: err}
+			return
+		}
+		v.events <- versusEventMsg{kind: kind, data: data}
+	}
+}
+
+// RunHost starts a versus lobby listening on port and blocks until
+// the match (and process) ends.
+func RunHost(port int, name string, th theme.Theme) error {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	m := initialModel(th)
+	m.versus = &versusSession{
+		listener: ln,
+		isHost:   true,
+		selfName: name,
+		events:   make(chan versusEventMsg, 8),
+	}
+
+	p := tea.NewProgram(m)
+	return p.Start()
+}
+
+// RunJoin connects to a host's versus lobby and blocks until the
+// match (and process) ends.
+func RunJoin(addr, name string, th theme.Theme) error {
+	raw, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	conn := protocol.NewConn(raw)
+	if err := conn.Send(protocol.Join{Type: protocol.TypeJoin, Name: name}); err != nil {
+		return err
+	}
+
+	m := initialModel(th)
+	m.versus = &versusSession{
+		conn:     conn,
+		raw:      raw,
+		addr:     addr,
+		isHost:   false,
+		selfName: name,
+		events:   make(chan versusEventMsg, 8),
+	}
+	m.state = stateLobby
+
+	go versusReadLoop(m.versus, conn)
+
+	p := tea.NewProgram(m)
+	return p.Start()
+}
+
+// acceptOpponent blocks in the background waiting for the single
+// opponent a head-to-head match needs, then reports it as an event.
+func acceptOpponent(v *versusSession) {
+	raw, err := v.listener.Accept()
+	if err != nil {
+		v.events <- versusEventMsg{kind: versusKindDisconnected, err: err}
+		return
+	}
+	conn := protocol.NewConn(raw)
+
+	kind, data, err := conn.Receive()
+	if err != nil || kind != protocol.TypeJoin {
+		v.events <- versusEventMsg{kind: versusKindDisconnected, err: err}
+		return
+	}
+	var join protocol.Join
+	_ = json.Unmarshal(data, &join)
+
+	v.setConn(raw, conn)
+	v.setOppName(join.Name)
+
+	go versusReadLoop(v, conn)
+	v.events <- versusEventMsg{kind: versusKindConnected}
+}
+
+// acceptReconnect waits, up to deadline, for the opponent to
+// reconnect to the host's still-open listener after a drop, then
+// reports it as a (re)connected event. A failure or timeout is not
+// itself reported — updateVersus's own tickMsg handling ends the
+// match once reconnectDeadline passes.
+func acceptReconnect(v *versusSession, deadline time.Time) {
+	if tl, ok := v.listener.(*net.TCPListener); ok {
+		_ = tl.SetDeadline(deadline)
+		defer tl.SetDeadline(time.Time{})
+	}
+
+	raw, err := v.listener.Accept()
+	if err != nil {
+		return
+	}
+
+	conn := protocol.NewConn(raw)
+	kind, data, err := conn.Receive()
+	if err != nil || kind != protocol.TypeJoin {
+		return
+	}
+	var join protocol.Join
+	_ = json.Unmarshal(data, &join)
+
+	v.setConn(raw, conn)
+	v.setOppName(join.Name)
+
+	go versusReadLoop(v, conn)
+	v.events <- versusEventMsg{kind: versusKindConnected}
+}
+
+// redialReconnect retries dialing addr, up to deadline, after the
+// client's connection to the host drops, then reports success as a
+// (re)connected event.
+func redialReconnect(v *versusSession, addr string, deadline time.Time) {
+	for time.Now().Before(deadline) {
+		raw, err := net.DialTimeout("tcp", addr, time.Second)
+		if err != nil {
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+
+		conn := protocol.NewConn(raw)
+		if err := conn.Send(protocol.Join{Type: protocol.TypeJoin, Name: v.selfName}); err != nil {
+			continue
+		}
+
+		v.setConn(raw, conn)
+		go versusReadLoop(v, conn)
+		v.events <- versusEventMsg{kind: versusKindConnected}
+		return
+	}
+}
+
+// startVersusRound is called once both sides are connected (host) or
+// once the seed arrives (client), and kicks off the shared quiz.
+func (m *model) startVersusRound(seed int64, difficulty string, duration int) tea.Cmd {
+	m.difficulty = difficulty
+	m.timeLimit = duration
+	m.timeRemaining = time.Duration(duration) * time.Second
+	m.usedQuestions = make(map[string]struct{})
+	m.rng = rand.New(rand.NewSource(seed))
+	m.state = stateVersus
+	m.questionStart = time.Now()
+
+	// The host generates and broadcasts the first question separately
+	// via nextVersusQuestion, right after calling this.
+	return tea.Batch(tick(), fuseTick(), pulseTick(), waitVersusEvent(m.versus))
+}
+
+// nextVersusQuestion is host-only: it advances the shared quiz and
+// broadcasts the new question so both sides stay in lockstep.
+func (m *model) nextVersusQuestion() {
+	m.currentQ = generateQuestion(m.difficulty, m.usedQuestions, nil, m.rng)
+	_ = m.versus.getConn().Send(protocol.Question{
+		Type:   protocol.TypeQuestion,
+		ID:     m.currentQ.UniqueID,
+		Text:   m.currentQ.Text,
+		Answer: m.currentQ.Answer,
+		OpType: m.currentQ.OpType,
+	})
+}
+
+// renderOpponentBar draws the opponent's progress alongside the
+// player's own countdown bar.
+func renderOpponentBar(v *versusSession) string {
+	if v == nil {
+		return ""
+	}
+	name := v.getOppName()
+	if name == "" {
+		name = "Opponent"
+	}
+
+	status := ""
+	if v.disconnected {
+		remaining := time.Until(v.reconnectDeadline).Round(time.Second)
+		if remaining < 0 {
+			remaining = 0
+		}
+		status = fmt.Sprintf(" (disconnected, %s to reconnect)", remaining)
+	}
+
+	return fmt.Sprintf("%s: %ds left, score %d%s", name, v.oppRemain, v.oppScore, status)
+}
+
+// renderScoreboard is a one-line "You X — Opponent Y" summary.
+func renderScoreboard(m model) string {
+	oppName := m.versus.getOppName()
+	if oppName == "" {
+		oppName = "Opponent"
+	}
+	return fmt.Sprintf("%s %d — %s %d", m.versus.selfName, m.versus.selfScore, oppName, m.versus.oppScore)
+}
+
+// renderVersusOutcome summarizes a finished versus match: the final
+// score line and a win/lose/tie verdict from the player's perspective.
+func renderVersusOutcome(v *versusSession) string {
+	oppName := v.getOppName()
+	if oppName == "" {
+		oppName = "Opponent"
+	}
+
+	verdict := "It's a tie!"
+	switch {
+	case v.selfScore > v.oppScore:
+		verdict = "You win!"
+	case v.selfScore < v.oppScore:
+		verdict = "You lose."
+	}
+
+	return fmt.Sprintf("%s\n%s %d — %s %d\n\n", verdict, v.selfName, v.selfScore, oppName, v.oppScore)
+}
+
+// lobbyView renders the waiting-for-opponent / waiting-for-seed screen.
+func lobbyView(m model) string {
+	if m.versus.isHost {
+		return clearScreen + "Hosting versus match — waiting for an opponent to join...\n[q] to quit"
+	}
+	return clearScreen + fmt.Sprintf("Joined as %s — waiting for the host to start...\n[q] to quit", m.versus.selfName)
+}
+
+// isTie reports whether the match ended in a draw.
+func isTie(v *versusSession) bool { return v.selfScore == v.oppScore }
+
+// updateLobby drives stateLobby: the host waits for acceptOpponent to
+// report a connection, the client waits for the host's Seed.
+func (m model) updateLobby(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case versusEventMsg:
+		if msg.err != nil && msg.kind == versusKindDisconnected {
+			return m, tea.Quit
+		}
+
+		if m.versus.isHost {
+			if msg.kind == versusKindConnected {
+				seed := time.Now().UnixNano()
+				_ = m.versus.getConn().Send(protocol.Seed{
+					Type: protocol.TypeSeed, Seed: seed,
+					Difficulty: m.difficulty, Duration: m.timeLimit,
+				})
+				cmd := m.startVersusRound(seed, m.difficulty, m.timeLimit)
+				m.nextVersusQuestion()
+				return m, cmd
+			}
+		} else if msg.kind == protocol.TypeSeed {
+			var seed protocol.Seed
+			_ = json.Unmarshal(msg.data, &seed)
+			cmd := m.startVersusRound(seed.Seed, seed.Difficulty, seed.Duration)
+			return m, cmd
+		}
+		return m, waitVersusEvent(m.versus)
+
+	case tea.KeyMsg:
+		if msg.String() == "q" {
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// updateVersus drives stateVersus: the same quiz loop as stateRunning,
+// plus keeping the network connection and scoreboard in sync.
+func (m model) updateVersus(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tickMsg:
+		if m.versus.disconnected && time.Now().After(m.versus.reconnectDeadline) {
+			m.state = stateResults
+			return m, nil
+		}
+
+		m.timeRemaining -= time.Second
+		_ = m.versus.getConn().Send(protocol.Tick{
+			Type: protocol.TypeTick, Player: m.versus.selfName,
+			Remaining: int(m.timeRemaining.Seconds()),
+		})
+
+		if m.timeRemaining <= 0 {
+			// Only the host decides how the round ends — if each side
+			// judged its own local countdown, a network hiccup could
+			// have the two sides disagree about whether it was a tie.
+			if m.versus.isHost {
+				if isTie(m.versus) {
+					m.versus.tieBreaker = true
+					m.timeRemaining = tieBreakerDuration * time.Second
+					m.usedQuestions = make(map[string]struct{})
+					_ = m.versus.getConn().Send(protocol.Result{Type: protocol.TypeResult, TieBreaker: true})
+					_ = m.versus.getConn().Send(protocol.Seed{
+						Type: protocol.TypeSeed, Seed: m.rng.Int63(),
+						Difficulty: m.difficulty, Duration: tieBreakerDuration,
+					})
+					m.nextVersusQuestion()
+					return m, tea.Batch(tick(), fuseTick(), pulseTick(), waitVersusEvent(m.versus))
+				}
+				_ = m.versus.getConn().Send(protocol.Result{Type: protocol.TypeResult, TieBreaker: false})
+				m.state = stateResults
+				return m, nil
+			}
+			// The client freezes at zero and waits for the host's
+			// Result (and, for a tie, the Seed that follows it)
+			// instead of racing its own countdown to a conclusion.
+			return m, nil
+		}
+		cmds = append(cmds, tick())
+
+	case fuseTickMsg:
+		m.fuseFrameIndex = (m.fuseFrameIndex + 1) % len(m.fuseFrames)
+		return m, fuseTick()
+
+	case pulseTickMsg:
+		return m, pulseTick()
+
+	case versusEventMsg:
+		return m.handleVersusEvent(msg)
+
+	case flashDoneMsg:
+		m.flashActive = false
+		m.flashColorOverride = ""
+		m.flashBarAdjust = 0
+		m.textInput.Reset()
+		m.textInput.Focus()
+		m.suspendTick = false
+		m.questionStart = time.Now()
+
+	case flashFadeMsg:
+		if m.flashFadeSteps > 0 {
+			m.flashFadeSteps--
+			if m.flashFadeSteps == 0 {
+				m.flashColorOverride = ""
+			} else {
+				return m, flashFadeTick()
+			}
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			userInput := m.textInput.Value()
+			var userAns int
+			_, err := fmt.Sscanf(userInput, "%d", &userAns)
+			if err == nil {
+				correct := userAns == m.currentQ.Answer
+				m.answers = append(m.answers, AnswerRecord{
+					Question: m.currentQ, Correct: correct,
+					Duration: time.Since(m.questionStart), UserInput: userInput,
+				})
+
+				if correct {
+					m.versus.selfScore++
+					m.flashText, m.flashColor, m.flashBarAdjust, m.flashColorOverride = "Correct!", "green", 1, "brightGreen"
+				} else {
+					m.flashText, m.flashColor, m.flashBarAdjust, m.flashColorOverride = "Incorrect!", "red", -1, "brightRed"
+				}
+
+				_ = m.versus.getConn().Send(protocol.Answer{Type: protocol.TypeAnswer, Player: m.versus.selfName, Correct: correct})
+				_ = m.versus.getConn().Send(protocol.Score{Type: protocol.TypeScore, Player: m.versus.selfName, Score: m.versus.selfScore})
+
+				if m.versus.isHost {
+					m.nextVersusQuestion()
+				}
+
+				m.flashFadeSteps = 3
+				m.suspendTick = true
+				m.flashActive = true
+				m.textInput.Blur()
+				return m, tea.Batch(flashTimeout(), flashFadeTick())
+			}
+		case "q":
+			return m, tea.Quit
+		}
+	}
+
+	var inputCmd tea.Cmd
+	m.textInput, inputCmd = m.textInput.Update(msg)
+	cmds = append(cmds, inputCmd)
+	return m, tea.Batch(cmds...)
+}
+
+// handleVersusEvent applies one inbound protocol message to the
+// scoreboard/opponent state during stateVersus.
+func (m model) handleVersusEvent(msg versusEventMsg) (tea.Model, tea.Cmd) {
+	switch msg.kind {
+	case versusKindDisconnected:
+		m.versus.disconnected = true
+		m.versus.reconnectDeadline = time.Now().Add(reconnectGrace)
+		if m.versus.isHost {
+			go acceptReconnect(m.versus, m.versus.reconnectDeadline)
+		} else {
+			go redialReconnect(m.versus, m.versus.addr, m.versus.reconnectDeadline)
+		}
+
+	case versusKindConnected:
+		m.versus.disconnected = false
+
+	case protocol.TypeTick:
+		var t protocol.Tick
+		if json.Unmarshal(msg.data, &t) == nil {
+			m.versus.oppRemain = t.Remaining
+			m.versus.disconnected = false
+		}
+
+	case protocol.TypeScore:
+		var s protocol.Score
+		if json.Unmarshal(msg.data, &s) == nil {
+			m.versus.oppScore = s.Score
+		}
+
+	case protocol.TypeQuestion:
+		if !m.versus.isHost {
+			var q protocol.Question
+			if json.Unmarshal(msg.data, &q) == nil {
+				m.currentQ = questions.Question{Text: q.Text, Answer: q.Answer, OpType: q.OpType, UniqueID: q.ID}
+				m.questionStart = time.Now()
+				m.textInput.Reset()
+				m.textInput.Focus()
+			}
+		}
+
+	case protocol.TypeResult:
+		// The host's authoritative call on how the round ended. A
+		// tie-breaker's Seed follows right behind this, so there's
+		// nothing more to do here than let the non-tie case end the
+		// match on both sides instead of just the host's.
+		var r protocol.Result
+		if json.Unmarshal(msg.data, &r) == nil && !r.TieBreaker {
+			m.state = stateResults
+		}
+
+	case protocol.TypeSeed:
+		// A Seed mid-match signals a tie-breaker round (the host's
+		// Result{TieBreaker:true} arrived just before this). Resume
+		// stateVersus and re-arm the tick/fuse/pulse loop the client
+		// froze when its local countdown hit zero.
+		var seed protocol.Seed
+		if json.Unmarshal(msg.data, &seed) == nil {
+			m.versus.tieBreaker = true
+			m.rng = rand.New(rand.NewSource(seed.Seed))
+			m.usedQuestions = make(map[string]struct{})
+			m.timeRemaining = time.Duration(seed.Duration) * time.Second
+			m.state = stateVersus
+			return m, tea.Batch(tick(), fuseTick(), pulseTick(), waitVersusEvent(m.versus))
+		}
+	}
+
+	return m, waitVersusEvent(m.versus)
+}
@@ -0,0 +1,134 @@
+// Package net implements the line-delimited JSON protocol spoken
+// between a versus-mode host and its joined players.
+package net
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// MessageType identifies the shape of a protocol message.
+type MessageType string
+
+const (
+	TypeJoin     MessageType = "join"
+	TypeSeed     MessageType = "seed"
+	TypeQuestion MessageType = "question"
+	TypeAnswer   MessageType = "answer"
+	TypeTick     MessageType = "tick"
+	TypeScore    MessageType = "score"
+	TypeResult   MessageType = "result"
+)
+
+// Envelope carries just the Type field, enough to dispatch a raw
+// message to the right concrete struct.
+type Envelope struct {
+	Type MessageType `json:"type"`
+}
+
+// Join is sent by a joining player immediately after connecting.
+type Join struct {
+	Type MessageType `json:"type"`
+	Name string      `json:"name"`
+}
+
+// Seed is broadcast by the host once a player joins, synchronizing
+// the RNG seed, difficulty and duration both sides play with.
+type Seed struct {
+	Type       MessageType `json:"type"`
+	Seed       int64       `json:"seed"`
+	Difficulty string      `json:"difficulty"`
+	Duration   int         `json:"duration"`
+}
+
+// Question announces the question both sides should now be showing.
+// The full text/answer/opType travel with it (not just an ID) since
+// each side grades the player's input locally.
+type Question struct {
+	Type   MessageType `json:"type"`
+	ID     string      `json:"id"`
+	Text   string      `json:"text"`
+	Answer int         `json:"answer"`
+	OpType string      `json:"opType"`
+}
+
+// Answer reports one player's result for the current question.
+type Answer struct {
+	Type    MessageType `json:"type"`
+	Player  string      `json:"player"`
+	Correct bool        `json:"correct"`
+}
+
+// Tick reports the sender's remaining time, driving the opponent's
+// progress bar on the receiving side.
+type Tick struct {
+	Type      MessageType `json:"type"`
+	Player    string      `json:"player"`
+	Remaining int         `json:"remaining"`
+}
+
+// Score reports a player's current score, used to render the
+// scoreboard and to detect a tie at the end of the round.
+type Score struct {
+	Type   MessageType `json:"type"`
+	Player string      `json:"player"`
+	Score  int         `json:"score"`
+}
+
+// Result is the host's authoritative decision for how a round just
+// ended: TieBreaker true means a Seed for another round follows;
+// false means the match is over and both sides should show results.
+// Only the host sends this — clients don't decide the outcome from
+// their own local timer, since a network hiccup could desync it from
+// the host's.
+type Result struct {
+	Type       MessageType `json:"type"`
+	TieBreaker bool        `json:"tieBreaker"`
+}
+
+// Conn is a line-delimited JSON connection: one JSON object per line,
+// in either direction.
+type Conn struct {
+	w       io.Writer
+	scanner *bufio.Scanner
+}
+
+// NewConn wraps rw as a line-delimited JSON connection.
+func NewConn(rw io.ReadWriter) *Conn {
+	return &Conn{w: rw, scanner: bufio.NewScanner(rw)}
+}
+
+// Send marshals v (one of the message structs above) and writes it
+// as a single line.
+func (c *Conn) Send(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = c.w.Write(data)
+	return err
+}
+
+// Receive reads the next line and reports its Type, leaving the
+// caller to unmarshal the returned bytes into the matching struct.
+func (c *Conn) Receive() (MessageType, []byte, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return "", nil, err
+		}
+		return "", nil, io.EOF
+	}
+
+	line := c.scanner.Bytes()
+	var env Envelope
+	if err := json.Unmarshal(line, &env); err != nil {
+		return "", nil, err
+	}
+
+	// scanner.Bytes() is reused on the next Scan, so copy it out.
+	cp := make([]byte, len(line))
+	copy(cp, line)
+	return env.Type, cp, nil
+}
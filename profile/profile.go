@@ -0,0 +1,232 @@
+// Package profile persists per-player history and schedules questions
+// using an SM-2-style spaced-repetition algorithm.
+package profile
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const configDirName = "oa-quiz-game"
+const profileFileName = "profile.json"
+
+// Card tracks the spaced-repetition state for a single OpType.
+type Card struct {
+	OpType   string    `json:"opType"`
+	Easiness float64   `json:"easiness"`
+	Interval int       `json:"interval"`
+	Reps     int       `json:"reps"`
+	DueAt    time.Time `json:"dueAt"`
+}
+
+// Stats summarizes overall performance across all sessions.
+type Stats struct {
+	Attempts   int `json:"attempts"`
+	Correct    int `json:"correct"`
+	Streak     int `json:"streak"`
+	BestStreak int `json:"bestStreak"`
+}
+
+// Profile is the persisted record of a player's history.
+type Profile struct {
+	Cards map[string]*Card `json:"cards"`
+	Stats Stats            `json:"stats"`
+}
+
+// New returns an empty profile ready to record answers.
+func New() *Profile {
+	return &Profile{Cards: make(map[string]*Card)}
+}
+
+// path returns ~/.config/oa-quiz-game/profile.json.
+func path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", configDirName, profileFileName), nil
+}
+
+// Load reads the profile from disk, returning a fresh Profile if none
+// exists yet.
+func Load() (*Profile, error) {
+	p, err := path()
+	if err != nil {
+		return New(), err
+	}
+
+	data, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return New(), nil
+	} else if err != nil {
+		return New(), err
+	}
+
+	prof := New()
+	if err := json.Unmarshal(data, prof); err != nil {
+		return New(), err
+	}
+	if prof.Cards == nil {
+		prof.Cards = make(map[string]*Card)
+	}
+	return prof, nil
+}
+
+// Save writes the profile to ~/.config/oa-quiz-game/profile.json,
+// creating the directory if needed.
+func (p *Profile) Save() error {
+	dest, err := path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0o644)
+}
+
+// gradeAnswer scores an answer 0-5 from correctness and how the
+// response time compares to the expected duration.
+func gradeAnswer(correct bool, duration, expected time.Duration) int {
+	if !correct {
+		if duration > 0 && duration < expected {
+			return 2 // wrong but fast: probably a careless slip
+		}
+		return 0
+	}
+
+	if expected <= 0 {
+		return 5
+	}
+
+	ratio := float64(duration) / float64(expected)
+	switch {
+	case ratio <= 0.5:
+		return 5
+	case ratio <= 1.0:
+		return 4
+	case ratio <= 1.5:
+		return 3
+	default:
+		return 3
+	}
+}
+
+// Record grades an answer and updates the SM-2 card for opType.
+func (p *Profile) Record(opType string, correct bool, duration, expected time.Duration) {
+	card, ok := p.Cards[opType]
+	if !ok {
+		card = &Card{OpType: opType, Easiness: 2.5, Interval: 1, Reps: 0}
+		p.Cards[opType] = card
+	}
+
+	q := float64(gradeAnswer(correct, duration, expected))
+
+	card.Easiness = card.Easiness + (0.1 - (5-q)*(0.08+(5-q)*0.02))
+	if card.Easiness < 1.3 {
+		card.Easiness = 1.3
+	}
+
+	if q < 3 {
+		card.Reps = 0
+		card.Interval = 1
+	} else {
+		card.Reps++
+		switch card.Reps {
+		case 1:
+			card.Interval = 1
+		case 2:
+			card.Interval = 6
+		default:
+			card.Interval = int(math.Round(float64(card.Interval) * card.Easiness))
+		}
+	}
+	card.DueAt = time.Now().Add(time.Duration(card.Interval) * 24 * time.Hour)
+
+	p.Stats.Attempts++
+	if correct {
+		p.Stats.Correct++
+		p.Stats.Streak++
+		if p.Stats.Streak > p.Stats.BestStreak {
+			p.Stats.BestStreak = p.Stats.Streak
+		}
+	} else {
+		p.Stats.Streak = 0
+	}
+}
+
+// DueOpType picks an OpType to bias question generation toward,
+// weighting currently-due cards by lowest easiness first. It returns
+// "" when nothing is due, so callers fall back to a fresh question.
+// rng drives the weighted pick, so callers threading a seeded RNG
+// (e.g. for headless reproducibility) get deterministic results.
+func (p *Profile) DueOpType(now time.Time, rng *rand.Rand) string {
+	var due []*Card
+	for _, c := range p.Cards {
+		if !c.DueAt.After(now) {
+			due = append(due, c)
+		}
+	}
+	if len(due) == 0 {
+		return ""
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].Easiness < due[j].Easiness })
+
+	totalWeight := 0.0
+	weights := make([]float64, len(due))
+	for i, c := range due {
+		w := 3.0 - c.Easiness // lower easiness -> higher weight
+		if w < 0.1 {
+			w = 0.1
+		}
+		weights[i] = w
+		totalWeight += w
+	}
+
+	r := rng.Float64() * totalWeight
+	for i, w := range weights {
+		r -= w
+		if r <= 0 {
+			return due[i].OpType
+		}
+	}
+	return due[len(due)-1].OpType
+}
+
+// Accuracy returns the fraction of correct answers across all sessions.
+func (p *Profile) Accuracy() float64 {
+	if p.Stats.Attempts == 0 {
+		return 0
+	}
+	return float64(p.Stats.Correct) / float64(p.Stats.Attempts)
+}
+
+// WeakestOpTypes returns up to n OpTypes sorted by lowest easiness,
+// i.e. the concepts the player struggles with most.
+func (p *Profile) WeakestOpTypes(n int) []string {
+	cards := make([]*Card, 0, len(p.Cards))
+	for _, c := range p.Cards {
+		cards = append(cards, c)
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Easiness < cards[j].Easiness })
+
+	if n > len(cards) {
+		n = len(cards)
+	}
+	out := make([]string, n)
+	for i := 0; i < n; i++ {
+		out[i] = cards[i].OpType
+	}
+	return out
+}
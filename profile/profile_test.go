@@ -0,0 +1,91 @@
+package profile
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestGradeAnswer(t *testing.T) {
+	const expected = 5 * time.Second
+
+	cases := []struct {
+		name     string
+		correct  bool
+		duration time.Duration
+		want     int
+	}{
+		{"wrong and slow", false, 10 * time.Second, 0},
+		{"wrong but fast", false, 2 * time.Second, 2},
+		{"correct, very fast", true, 1 * time.Second, 5},
+		{"correct, at expected pace", true, 5 * time.Second, 4},
+		{"correct, a bit slow", true, 7 * time.Second, 3},
+		{"correct, very slow", true, 20 * time.Second, 3},
+	}
+
+	for _, c := range cases {
+		if got := gradeAnswer(c.correct, c.duration, expected); got != c.want {
+			t.Errorf("%s: gradeAnswer(%v, %v, %v) = %d, want %d", c.name, c.correct, c.duration, expected, got, c.want)
+		}
+	}
+}
+
+func TestRecordUpdatesCardAndStats(t *testing.T) {
+	p := New()
+	const expected = 5 * time.Second
+
+	// q=5 (correct, very fast): easiness grows, reps/interval advance.
+	p.Record("add", true, 1*time.Second, expected)
+	card := p.Cards["add"]
+	if card == nil {
+		t.Fatal("Record did not create a card for a new OpType")
+	}
+
+	wantEasiness := 2.5 + (0.1 - 0*(0.08+0*0.02))
+	if math.Abs(card.Easiness-wantEasiness) > 1e-9 {
+		t.Errorf("after one correct answer, easiness = %v, want %v", card.Easiness, wantEasiness)
+	}
+	if card.Reps != 1 || card.Interval != 1 {
+		t.Errorf("after first correct rep, got reps=%d interval=%d, want reps=1 interval=1", card.Reps, card.Interval)
+	}
+	if p.Stats.Attempts != 1 || p.Stats.Correct != 1 || p.Stats.Streak != 1 {
+		t.Errorf("stats after one correct answer = %+v, want attempts=1 correct=1 streak=1", p.Stats)
+	}
+
+	// A wrong answer resets reps/interval and the streak, regardless
+	// of easiness.
+	p.Record("add", false, 10*time.Second, expected)
+	if card.Reps != 0 || card.Interval != 1 {
+		t.Errorf("after a miss, got reps=%d interval=%d, want reps=0 interval=1", card.Reps, card.Interval)
+	}
+	if p.Stats.Streak != 0 {
+		t.Errorf("after a miss, streak = %d, want 0", p.Stats.Streak)
+	}
+}
+
+func TestDueOpTypeIsDeterministicForASeed(t *testing.T) {
+	now := time.Now()
+	p := New()
+	p.Cards["add"] = &Card{OpType: "add", Easiness: 1.5, DueAt: now.Add(-time.Hour)}
+	p.Cards["sub"] = &Card{OpType: "sub", Easiness: 2.8, DueAt: now.Add(-time.Hour)}
+	p.Cards["mul"] = &Card{OpType: "mul", Easiness: 2.0, DueAt: now.Add(time.Hour)} // not due
+
+	got := p.DueOpType(now, rand.New(rand.NewSource(42)))
+	want := p.DueOpType(now, rand.New(rand.NewSource(42)))
+	if got != want {
+		t.Errorf("DueOpType with the same seed returned %q then %q, want identical picks", got, want)
+	}
+	if got == "mul" {
+		t.Error("DueOpType picked a card that isn't due yet")
+	}
+}
+
+func TestDueOpTypeEmptyWhenNothingDue(t *testing.T) {
+	p := New()
+	p.Cards["add"] = &Card{OpType: "add", Easiness: 2.5, DueAt: time.Now().Add(time.Hour)}
+
+	if got := p.DueOpType(time.Now(), rand.New(rand.NewSource(1))); got != "" {
+		t.Errorf("DueOpType with nothing due = %q, want \"\"", got)
+	}
+}
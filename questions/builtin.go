@@ -0,0 +1,194 @@
+package questions
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+func init() {
+	Register(firstGrade{})
+	Register(thirdGrade{})
+	Register(fifthGrade{})
+	Register(algebra{})
+}
+
+// builtinOrder fixes the menu order of the shipped difficulties;
+// packs are appended after these, sorted alphabetically.
+var builtinOrder = []string{"1st Grade", "3rd Grade", "5th Grade", "Algebra"}
+
+// uniqueID returns an ID unused within used, retrying the generator
+// closure until it produces a fresh one.
+func uniqueID(difficulty string, rng *rand.Rand, used map[string]struct{}) string {
+	for {
+		id := fmt.Sprintf("%s|%d", difficulty, rng.Int())
+		if _, exists := used[id]; !exists {
+			used[id] = struct{}{}
+			return id
+		}
+	}
+}
+
+type firstGrade struct{}
+
+func (firstGrade) Name() string { return "1st Grade" }
+
+func (g firstGrade) Generate(rng *rand.Rand, used map[string]struct{}) Question {
+	a, b := rng.Intn(11), rng.Intn(11)
+	var op, opType string
+	var answer int
+	if rng.Intn(2) == 0 {
+		op, opType = "+", "addition"
+		answer = a + b
+	} else {
+		if a < b {
+			a, b = b, a // prevent negative
+		}
+		op, opType = "-", "subtraction"
+		answer = a - b
+	}
+
+	return Question{
+		Text:     fmt.Sprintf("%d %s %d = ?", a, op, b),
+		Answer:   answer,
+		OpType:   opType,
+		UniqueID: uniqueID(g.Name(), rng, used),
+	}
+}
+
+type thirdGrade struct{}
+
+func (thirdGrade) Name() string { return "3rd Grade" }
+
+func (g thirdGrade) Generate(rng *rand.Rand, used map[string]struct{}) Question {
+	a, b := rng.Intn(21), rng.Intn(21)
+	ops := []string{"+", "-", "*"}
+	op := ops[rng.Intn(len(ops))]
+
+	var answer int
+	switch op {
+	case "+":
+		answer = a + b
+	case "-":
+		answer = a - b
+	case "*":
+		// Single-digit multiplication only
+		a, b = rng.Intn(10), rng.Intn(10)
+		answer = a * b
+	}
+
+	opType := map[string]string{"+": "addition", "-": "subtraction", "*": "multiplication"}[op]
+	return Question{
+		Text:     fmt.Sprintf("%d %s %d = ?", a, op, b),
+		Answer:   answer,
+		OpType:   opType,
+		UniqueID: uniqueID(g.Name(), rng, used),
+	}
+}
+
+type fifthGrade struct{}
+
+func (fifthGrade) Name() string { return "5th Grade" }
+
+func (g fifthGrade) Generate(rng *rand.Rand, used map[string]struct{}) Question {
+	ops := []string{"+", "-", "*", "/"}
+	op := ops[rng.Intn(len(ops))]
+
+	var a, b, answer int
+	switch op {
+	case "+":
+		a, b = rng.Intn(90)+10, rng.Intn(90)+10
+		answer = a + b
+	case "-":
+		a, b = rng.Intn(90)+10, rng.Intn(90)+10
+		answer = a - b
+	case "*":
+		// a and b ≤ 15
+		a, b = rng.Intn(15)+1, rng.Intn(15)+1
+		answer = a * b
+	case "/":
+		// Divisor max 15, dividend up to 3 digits
+		b = rng.Intn(15) + 1
+		answer = rng.Intn(20) + 1
+		a = b * answer // ensures whole number
+	}
+
+	opType := map[string]string{
+		"+": "addition", "-": "subtraction", "*": "multiplication", "/": "division",
+	}[op]
+	return Question{
+		Text:     fmt.Sprintf("%d %s %d = ?", a, op, b),
+		Answer:   answer,
+		OpType:   opType,
+		UniqueID: uniqueID(g.Name(), rng, used),
+	}
+}
+
+type algebra struct{}
+
+func (algebra) Name() string { return "Algebra" }
+
+func (g algebra) Generate(rng *rand.Rand, used map[string]struct{}) Question {
+	var text, opType string
+	var answer int
+
+	switch rng.Intn(7) {
+	case 0: // x + n = m
+		x := rng.Intn(41) - 20
+		n := rng.Intn(10) + 1
+		text = fmt.Sprintf("x + %d = %d. What is x?", n, x+n)
+		answer = x
+		opType = "algebra_addition"
+
+	case 1: // x - n = m
+		x := rng.Intn(41) - 20
+		n := rng.Intn(5) + 1
+		text = fmt.Sprintf("x - %d = %d. What is x?", n, x-n)
+		answer = x
+		opType = "algebra_subtraction"
+
+	case 2: // n + x = m
+		x := rng.Intn(41) - 20
+		n := rng.Intn(10) + 1
+		text = fmt.Sprintf("%d + x = %d. What is x?", n, x+n)
+		answer = x
+		opType = "algebra_addition"
+
+	case 3: // n - x = m
+		x := rng.Intn(41) - 20
+		n := rng.Intn(10) + x
+		text = fmt.Sprintf("%d - x = %d. What is x?", n, n-x)
+		answer = x
+		opType = "algebra_subtraction"
+
+	case 4: // x * n = m
+		x := rng.Intn(41) - 20
+		n := rng.Intn(6) + 1
+		text = fmt.Sprintf("x * %d = %d. What is x?", n, x*n)
+		answer = x
+		opType = "algebra_multiplication"
+
+	case 5: // x / n = m
+		n := rng.Intn(5) + 1   // still always positive
+		m := rng.Intn(21) - 10 // [-10, 10]
+		if m == 0 {
+			m = 1 // avoid zero
+		}
+		text = fmt.Sprintf("x ÷ %d = %d. What is x?", n, m)
+		answer = n * m
+		opType = "algebra_division"
+
+	case 6: // n * x = m
+		x := rng.Intn(41) - 20
+		n := rng.Intn(6) + 1
+		text = fmt.Sprintf("%d * x = %d. What is x?", n, x*n)
+		answer = x
+		opType = "algebra_multiplication"
+	}
+
+	return Question{
+		Text:     text,
+		Answer:   answer,
+		OpType:   opType,
+		UniqueID: uniqueID(g.Name(), rng, used),
+	}
+}
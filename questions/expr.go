@@ -0,0 +1,198 @@
+package questions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// evalTemplate substitutes vals into expr and evaluates it as an
+// integer arithmetic expression supporting + - * / %. It returns an
+// error if the expression is malformed or doesn't evaluate to a
+// whole number (e.g. "{a}/{b}" where a isn't a multiple of b).
+func evalTemplate(expr string, vals map[string]int) (int, error) {
+	return evalExpr(substituteVars(expr, vals))
+}
+
+// evalConstraint evaluates a simple comparison such as "a>b" or
+// "a+1<=b", reusing evalTemplate for each side.
+func evalConstraint(c string, vals map[string]int) (bool, error) {
+	for _, op := range []string{">=", "<=", "==", "!=", ">", "<"} {
+		idx := strings.Index(c, op)
+		if idx < 0 {
+			continue
+		}
+
+		lv, err := evalTemplate(c[:idx], vals)
+		if err != nil {
+			return false, err
+		}
+		rv, err := evalTemplate(c[idx+len(op):], vals)
+		if err != nil {
+			return false, err
+		}
+
+		switch op {
+		case ">=":
+			return lv >= rv, nil
+		case "<=":
+			return lv <= rv, nil
+		case "==":
+			return lv == rv, nil
+		case "!=":
+			return lv != rv, nil
+		case ">":
+			return lv > rv, nil
+		case "<":
+			return lv < rv, nil
+		}
+	}
+	return false, fmt.Errorf("questions: unrecognized constraint %q", c)
+}
+
+// exprParser is a small recursive-descent parser for integer
+// arithmetic: + - * / % with standard precedence and parentheses.
+type exprParser struct {
+	s []rune
+	i int
+}
+
+func evalExpr(s string) (int, error) {
+	p := &exprParser{s: []rune(s)}
+	v, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.i != len(p.s) {
+		return 0, fmt.Errorf("questions: unexpected trailing input in expression %q", s)
+	}
+	return v, nil
+}
+
+func (p *exprParser) skipSpace() {
+	for p.i < len(p.s) && p.s[p.i] == ' ' {
+		p.i++
+	}
+}
+
+func (p *exprParser) expr() (int, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return v, nil
+		}
+		switch p.s[p.i] {
+		case '+':
+			p.i++
+			rhs, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			v += rhs
+		case '-':
+			p.i++
+			rhs, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			v -= rhs
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *exprParser) term() (int, error) {
+	v, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return v, nil
+		}
+		switch p.s[p.i] {
+		case '*':
+			p.i++
+			rhs, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			v *= rhs
+		case '/':
+			p.i++
+			rhs, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			if rhs == 0 {
+				return 0, fmt.Errorf("questions: division by zero")
+			}
+			if v%rhs != 0 {
+				return 0, fmt.Errorf("questions: %d / %d is not integral", v, rhs)
+			}
+			v /= rhs
+		case '%':
+			p.i++
+			rhs, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			if rhs == 0 {
+				return 0, fmt.Errorf("questions: modulo by zero")
+			}
+			v %= rhs
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *exprParser) factor() (int, error) {
+	p.skipSpace()
+	if p.i >= len(p.s) {
+		return 0, fmt.Errorf("questions: unexpected end of expression")
+	}
+
+	if p.s[p.i] == '-' {
+		p.i++
+		v, err := p.factor()
+		return -v, err
+	}
+	if p.s[p.i] == '+' {
+		p.i++
+		return p.factor()
+	}
+	if p.s[p.i] == '(' {
+		p.i++
+		v, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+		p.skipSpace()
+		if p.i >= len(p.s) || p.s[p.i] != ')' {
+			return 0, fmt.Errorf("questions: expected closing parenthesis")
+		}
+		p.i++
+		return v, nil
+	}
+
+	start := p.i
+	for p.i < len(p.s) && p.s[p.i] >= '0' && p.s[p.i] <= '9' {
+		p.i++
+	}
+	if p.i == start {
+		return 0, fmt.Errorf("questions: expected number at position %d in %q", start, string(p.s))
+	}
+
+	v := 0
+	for _, r := range p.s[start:p.i] {
+		v = v*10 + int(r-'0')
+	}
+	return v, nil
+}
@@ -0,0 +1,92 @@
+package questions
+
+import "testing"
+
+func TestEvalExpr(t *testing.T) {
+	cases := []struct {
+		expr    string
+		want    int
+		wantErr bool
+	}{
+		{"2+3", 5, false},
+		{"2+3*4", 14, false},
+		{"(2+3)*4", 20, false},
+		{"10-3-2", 5, false},
+		{"10/2", 5, false},
+		{"10/3", 0, true},
+		{"10%3", 1, false},
+		{"10/0", 0, true},
+		{"10%0", 0, true},
+		{"-5+3", -2, false},
+		{"+5", 5, false},
+		{"  2 + 2  ", 4, false},
+		{"2+", 0, true},
+		{"2+2)", 0, true},
+		{"(2+2", 0, true},
+		{"abc", 0, true},
+	}
+
+	for _, c := range cases {
+		got, err := evalExpr(c.expr)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("evalExpr(%q) = %d, want error", c.expr, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("evalExpr(%q) unexpected error: %v", c.expr, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("evalExpr(%q) = %d, want %d", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestEvalTemplate(t *testing.T) {
+	vals := map[string]int{"a": 6, "b": 3}
+
+	got, err := evalTemplate("{a}/{b}", vals)
+	if err != nil {
+		t.Fatalf("evalTemplate: unexpected error: %v", err)
+	}
+	if got != 2 {
+		t.Errorf("evalTemplate({a}/{b}) = %d, want 2", got)
+	}
+
+	if _, err := evalTemplate("{a}/{c}", vals); err == nil {
+		t.Error("evalTemplate with an unsubstituted variable should error, got nil")
+	}
+}
+
+func TestEvalConstraint(t *testing.T) {
+	vals := map[string]int{"a": 5, "b": 3}
+
+	cases := []struct {
+		constraint string
+		want       bool
+	}{
+		{"{a}>{b}", true},
+		{"{a}<{b}", false},
+		{"{a}>=5", true},
+		{"{a}<=4", false},
+		{"{a}==5", true},
+		{"{a}!=5", false},
+	}
+
+	for _, c := range cases {
+		got, err := evalConstraint(c.constraint, vals)
+		if err != nil {
+			t.Errorf("evalConstraint(%q) unexpected error: %v", c.constraint, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("evalConstraint(%q) = %v, want %v", c.constraint, got, c.want)
+		}
+	}
+
+	if _, err := evalConstraint("{a}?{b}", vals); err == nil {
+		t.Error("evalConstraint with an unrecognized operator should error, got nil")
+	}
+}
@@ -0,0 +1,241 @@
+package questions
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// varRange is the inclusive [Min, Max] a template variable is sampled
+// from.
+type varRange struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+}
+
+type packTemplate struct {
+	Text        string              `yaml:"text"`
+	Answer      string              `yaml:"answer"`
+	Vars        map[string]varRange `yaml:"vars"`
+	Constraints []string            `yaml:"constraints"`
+}
+
+// loadedTemplate pairs a pack template with a known-good set of
+// variable values found for it at load time, used as a last-resort
+// fallback if runtime sampling ever comes up empty.
+type loadedTemplate struct {
+	tmpl     packTemplate
+	fallback map[string]int
+}
+
+type packFile struct {
+	Difficulty string         `yaml:"difficulty"`
+	OpType     string         `yaml:"opType"`
+	Templates  []packTemplate `yaml:"templates"`
+}
+
+// packGenerator is a QuestionGenerator backed by a user-authored pack
+// of fill-in-the-blank templates.
+type packGenerator struct {
+	difficulty string
+	opType     string
+	templates  []loadedTemplate
+}
+
+func (p *packGenerator) Name() string { return p.difficulty }
+
+// maxSampleAttempts bounds retries, across all templates combined,
+// when a template's constraints or integer-answer requirement keeps
+// rejecting sampled values. Without a bound, an unsatisfiable
+// template would spin Generate forever — it's called synchronously
+// from Update(), so that would hang the whole TUI.
+const maxSampleAttempts = 2000
+
+// Generate is resilient to bad pack content: LoadPacks already drops
+// templates it couldn't satisfy at all, but sampling can still come up
+// empty at runtime for a template that's merely hard to satisfy. In
+// that case it falls back to the known-good values found for that
+// template at load time rather than crashing the whole TUI session —
+// packs are untrusted, third-party content, not something a typo in
+// should be able to take the game down.
+func (p *packGenerator) Generate(rng *rand.Rand, used map[string]struct{}) Question {
+	var lastPick loadedTemplate
+
+	for attempt := 0; attempt < maxSampleAttempts; attempt++ {
+		lt := p.templates[rng.Intn(len(p.templates))]
+		lastPick = lt
+		vals := sampleVars(lt.tmpl.Vars, rng)
+
+		if !constraintsHold(lt.tmpl.Constraints, vals) {
+			continue
+		}
+
+		answer, err := evalTemplate(lt.tmpl.Answer, vals)
+		if err != nil {
+			continue // non-integral or malformed answer expression
+		}
+
+		return Question{
+			Text:     substituteVars(lt.tmpl.Text, vals),
+			Answer:   answer,
+			OpType:   p.opType,
+			UniqueID: uniqueID(p.difficulty, rng, used),
+		}
+	}
+
+	answer, err := evalTemplate(lastPick.tmpl.Answer, lastPick.fallback)
+	if err != nil {
+		// lastPick.fallback was itself verified at load time, so this
+		// would only happen if Generate is ever called with no
+		// templates at all — kept as a clear failure rather than a
+		// silent bad question.
+		panic(fmt.Sprintf("questions: pack %q has no usable template to fall back on", p.difficulty))
+	}
+	return Question{
+		Text:     substituteVars(lastPick.tmpl.Text, lastPick.fallback),
+		Answer:   answer,
+		OpType:   p.opType,
+		UniqueID: uniqueID(p.difficulty, rng, used),
+	}
+}
+
+func sampleVars(vars map[string]varRange, rng *rand.Rand) map[string]int {
+	vals := make(map[string]int, len(vars))
+	for name, r := range vars {
+		span := r.Max - r.Min + 1
+		if span <= 0 {
+			span = 1
+		}
+		vals[name] = r.Min + rng.Intn(span)
+	}
+	return vals
+}
+
+func substituteVars(s string, vals map[string]int) string {
+	for name, v := range vals {
+		s = strings.ReplaceAll(s, "{"+name+"}", strconv.Itoa(v))
+	}
+	return s
+}
+
+// constraintsHold evaluates simple comparisons like "a>b" after
+// substituting known variables.
+func constraintsHold(constraints []string, vals map[string]int) bool {
+	for _, c := range constraints {
+		ok, err := evalConstraint(c, vals)
+		if err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadPacks reads every *.yaml pack in dir and returns a generator
+// per pack. A missing directory is not an error — it simply means no
+// user packs are installed. A malformed or unusable pack is logged
+// and skipped rather than aborting every other pack in the directory.
+func LoadPacks(dir string) ([]QuestionGenerator, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var gens []QuestionGenerator
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+
+		g, err := loadPack(dir, entry.Name())
+		if err != nil {
+			log.Printf("questions: skipping pack %s: %v", entry.Name(), err)
+			continue
+		}
+		gens = append(gens, g)
+	}
+	return gens, nil
+}
+
+// loadPack reads and validates a single pack file, dropping any
+// template whose constraints or answer expression can't be satisfied
+// rather than failing the whole pack over one bad template.
+func loadPack(dir, name string) (*packGenerator, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("reading pack: %w", err)
+	}
+
+	var pf packFile
+	if err := yaml.Unmarshal(data, &pf); err != nil {
+		return nil, fmt.Errorf("parsing pack: %w", err)
+	}
+	if pf.Difficulty == "" || len(pf.Templates) == 0 {
+		return nil, fmt.Errorf("pack missing difficulty or templates")
+	}
+
+	var loaded []loadedTemplate
+	for _, tmpl := range pf.Templates {
+		fallback, ok := findSatisfyingVals(tmpl)
+		if !ok {
+			log.Printf("questions: pack %s: dropping template %q — never produced a valid (constraint-satisfying, integral) answer across %d sampled attempts", name, tmpl.Text, loadCheckAttempts)
+			continue
+		}
+		loaded = append(loaded, loadedTemplate{tmpl: tmpl, fallback: fallback})
+	}
+	if len(loaded) == 0 {
+		return nil, fmt.Errorf("pack has no usable templates")
+	}
+
+	return &packGenerator{
+		difficulty: pf.Difficulty,
+		opType:     pf.OpType,
+		templates:  loaded,
+	}, nil
+}
+
+// loadCheckAttempts bounds the load-time sanity sampling in
+// findSatisfyingVals.
+const loadCheckAttempts = 500
+
+// findSatisfyingVals samples tmpl's declared variable ranges looking
+// for a combination that holds its constraints and evaluates to an
+// integral answer — the same process Generate uses at runtime, so a
+// template this rejects really would make Generate spin. The first
+// satisfying combination found is returned for use as Generate's
+// last-resort fallback. Sampling with a fixed seed keeps pack loading
+// deterministic.
+func findSatisfyingVals(tmpl packTemplate) (map[string]int, bool) {
+	rng := rand.New(rand.NewSource(1))
+	for attempt := 0; attempt < loadCheckAttempts; attempt++ {
+		vals := sampleVars(tmpl.Vars, rng)
+		if !constraintsHold(tmpl.Constraints, vals) {
+			continue
+		}
+		if _, err := evalTemplate(tmpl.Answer, vals); err == nil {
+			return vals, true
+		}
+	}
+	return nil, false
+}
+
+// LoadAndRegisterPacks loads packs from dir and registers each as a
+// difficulty. Later packs with the same difficulty name replace
+// earlier ones, mirroring Register's override behavior.
+func LoadAndRegisterPacks(dir string) error {
+	gens, err := LoadPacks(dir)
+	if err != nil {
+		return err
+	}
+	for _, g := range gens {
+		Register(g)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package questions defines the pluggable question-generator registry
+// used to produce quiz questions for a given difficulty, including
+// user-authored packs loaded from disk.
+package questions
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Question is a single generated quiz question.
+type Question struct {
+	Text     string `json:"text"`
+	Answer   int    `json:"answer"`
+	OpType   string `json:"opType"`
+	UniqueID string `json:"uniqueId"`
+}
+
+// QuestionGenerator produces questions for one difficulty/pack.
+type QuestionGenerator interface {
+	// Name is the difficulty label shown in the menu, e.g. "5th Grade".
+	Name() string
+	// Generate returns a question not already present in used, keyed
+	// by Question.UniqueID.
+	Generate(rng *rand.Rand, used map[string]struct{}) Question
+}
+
+var registry = make(map[string]QuestionGenerator)
+
+// Register adds a generator to the registry, keyed by its Name().
+// Re-registering a name replaces the previous generator, so
+// user-authored packs may override a built-in difficulty.
+func Register(g QuestionGenerator) {
+	registry[g.Name()] = g
+}
+
+// Get looks up a registered generator by difficulty name.
+func Get(name string) (QuestionGenerator, bool) {
+	g, ok := registry[name]
+	return g, ok
+}
+
+// Names returns all registered difficulty names, built-ins first in
+// their original order followed by packs sorted alphabetically.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	order := func(name string) int {
+		for i, n := range builtinOrder {
+			if n == name {
+				return i
+			}
+		}
+		return len(builtinOrder)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		oi, oj := order(names[i]), order(names[j])
+		if oi != oj {
+			return oi < oj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Generate picks a generator for difficulty and produces a question
+// not already present in used. It panics if difficulty isn't
+// registered, since the menu is always populated from Names().
+func Generate(difficulty string, rng *rand.Rand, used map[string]struct{}) Question {
+	g, ok := Get(difficulty)
+	if !ok {
+		panic(fmt.Sprintf("questions: no generator registered for %q", difficulty))
+	}
+	return g.Generate(rng, used)
+}
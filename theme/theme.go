@@ -0,0 +1,127 @@
+// Package theme defines the named semantic color roles used to
+// render the quiz (the countdown bar, flash feedback, the fuse tip),
+// loaded from built-in or user-authored TOML themes.
+package theme
+
+import (
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a fully resolved set of styles for one semantic role each.
+// Built from a rawTheme, with colors stripped in favor of terminal
+// attributes when NoColor() is true.
+type Theme struct {
+	Name     string
+	BarHigh  lipgloss.Style
+	BarMid   lipgloss.Style
+	BarLow   lipgloss.Style
+	FlashOk  lipgloss.Style
+	FlashBad lipgloss.Style
+	Fuse     lipgloss.Style
+	TextDim  lipgloss.Style
+}
+
+// rawTheme is the on-disk TOML shape: one ANSI/hex color string per
+// semantic role.
+type rawTheme struct {
+	Name     string `toml:"name"`
+	BarHigh  string `toml:"bar_high"`
+	BarMid   string `toml:"bar_mid"`
+	BarLow   string `toml:"bar_low"`
+	FlashOk  string `toml:"flash_ok"`
+	FlashBad string `toml:"flash_bad"`
+	Fuse     string `toml:"fuse"`
+	TextDim  string `toml:"text_dim"`
+}
+
+var builtins = map[string]rawTheme{
+	"default": {
+		Name: "default", BarHigh: "10", BarMid: "11", BarLow: "88",
+		FlashOk: "82", FlashBad: "196", Fuse: "11", TextDim: "245",
+	},
+	"solarized": {
+		Name: "solarized", BarHigh: "#859900", BarMid: "#b58900", BarLow: "#dc322f",
+		FlashOk: "#2aa198", FlashBad: "#cb4b16", Fuse: "#b58900", TextDim: "#657b83",
+	},
+	"high-contrast": {
+		Name: "high-contrast", BarHigh: "15", BarMid: "11", BarLow: "9",
+		FlashOk: "15", FlashBad: "9", Fuse: "15", TextDim: "7",
+	},
+	"colorblind-deuteranopia": {
+		// Deuteranopia can't reliably distinguish red/green, so this
+		// theme is built entirely from the blue/orange axis instead.
+		Name: "colorblind-deuteranopia", BarHigh: "#0072B2", BarMid: "#E69F00", BarLow: "#D55E00",
+		FlashOk: "#0072B2", FlashBad: "#D55E00", Fuse: "#E69F00", TextDim: "#56B4E9",
+	},
+}
+
+// Names lists the built-in theme names.
+func Names() []string {
+	return []string{"default", "solarized", "high-contrast", "colorblind-deuteranopia"}
+}
+
+// NoColor reports whether color should be suppressed in favor of
+// plain terminal attributes (bold/underline/reverse), per the
+// NO_COLOR convention (https://no-color.org) or a "dumb" terminal.
+func NoColor() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return true
+	}
+	return os.Getenv("TERM") == "dumb"
+}
+
+// build resolves a rawTheme into styled Theme, substituting
+// attribute-only styling for color when NoColor() is true.
+func build(raw rawTheme) Theme {
+	if NoColor() {
+		return Theme{
+			Name:     raw.Name,
+			BarHigh:  lipgloss.NewStyle(),
+			BarMid:   lipgloss.NewStyle().Underline(true),
+			BarLow:   lipgloss.NewStyle().Reverse(true),
+			FlashOk:  lipgloss.NewStyle().Bold(true),
+			FlashBad: lipgloss.NewStyle().Reverse(true).Bold(true),
+			Fuse:     lipgloss.NewStyle().Reverse(true),
+			TextDim:  lipgloss.NewStyle().Faint(true),
+		}
+	}
+
+	return Theme{
+		Name:     raw.Name,
+		BarHigh:  lipgloss.NewStyle().Foreground(lipgloss.Color(raw.BarHigh)),
+		BarMid:   lipgloss.NewStyle().Foreground(lipgloss.Color(raw.BarMid)),
+		BarLow:   lipgloss.NewStyle().Foreground(lipgloss.Color(raw.BarLow)),
+		FlashOk:  lipgloss.NewStyle().Foreground(lipgloss.Color(raw.FlashOk)),
+		FlashBad: lipgloss.NewStyle().Foreground(lipgloss.Color(raw.FlashBad)),
+		Fuse:     lipgloss.NewStyle().Foreground(lipgloss.Color(raw.Fuse)),
+		TextDim:  lipgloss.NewStyle().Foreground(lipgloss.Color(raw.TextDim)),
+	}
+}
+
+// Default is the theme used when none is selected.
+func Default() Theme { return build(builtins["default"]) }
+
+// Load resolves name to a Theme, checking built-ins first and then
+// path (if non-empty) as a user-authored TOML file. An unknown name
+// with no matching file falls back to Default.
+func Load(name string, path string) (Theme, error) {
+	if raw, ok := builtins[name]; ok {
+		return build(raw), nil
+	}
+
+	if path == "" {
+		return Default(), nil
+	}
+
+	var raw rawTheme
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		return Default(), err
+	}
+	if raw.Name == "" {
+		raw.Name = name
+	}
+	return build(raw), nil
+}